@@ -0,0 +1,377 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package glue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gozix/di"
+	"golang.org/x/sync/errgroup"
+)
+
+type (
+	// CompositeBundle is a Bundle made of other bundles, e.g. one produced
+	// by Seq or Parallel. registerBundles flattens it into the dependency
+	// graph instead of treating it as an opaque leaf.
+	CompositeBundle interface {
+		Bundle
+		Children() []Bundle
+	}
+
+	// Preparer is an optional Bundle interface for independent pre-work
+	// that a Parallel group can run concurrently, before the (serialized)
+	// Build calls.
+	Preparer interface {
+		Prepare(ctx context.Context) error
+	}
+
+	// seqBundle builds its children in order, on the same di.Builder.
+	seqBundle struct {
+		name     string
+		children []Bundle
+	}
+
+	// parallelBundle builds independent children, without any ordering
+	// guarantee between them.
+	parallelBundle struct {
+		name     string
+		children []Bundle
+	}
+
+	// dependencyBundle decorates a Bundle with extra synthetic
+	// dependencies, used by flattenBundles to preserve a Seq's ordering
+	// once its children become individual graph nodes.
+	dependencyBundle struct {
+		Bundle
+		extra []string
+	}
+
+	// parallelGroup is shared by every flattened member of one Parallel
+	// call, so their Preparers - looked up against the original,
+	// pre-flattening bundles, not any flattenBundles wrapper - actually
+	// run concurrently, once, the first time any member is built.
+	parallelGroup struct {
+		once    sync.Once
+		err     error
+		members []Bundle
+	}
+
+	// parallelMember decorates one flattened child of a Parallel call, so
+	// that, even though the group itself never becomes a graph node (its
+	// children do, to support external dependencies on them by name), its
+	// Preparer semantics are not lost.
+	parallelMember struct {
+		Bundle
+		group *parallelGroup
+	}
+)
+
+// Seq combines bundles into a single Bundle that builds its children, in
+// order, on the same di.Builder. Its DependsOn is the union of the
+// children's dependencies, minus names internal to the sequence.
+func Seq(bundles ...Bundle) Bundle {
+	return &seqBundle{
+		name:     compositeName("seq", bundles),
+		children: bundles,
+	}
+}
+
+// Name implements Bundle.
+func (s *seqBundle) Name() string {
+	return s.name
+}
+
+// Build implements Bundle.
+func (s *seqBundle) Build(builder di.Builder) error {
+	for _, child := range s.children {
+		if err := child.Build(builder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DependsOn implements BundleDependsOn.
+func (s *seqBundle) DependsOn() []string {
+	return externalDependencies(s.children)
+}
+
+// Children implements CompositeBundle.
+func (s *seqBundle) Children() []Bundle {
+	return s.children
+}
+
+// Parallel combines bundles into a single Bundle whose children have no
+// dependency on one another. Any child implementing Preparer has its
+// Prepare method run concurrently under an errgroup.Group, the first
+// time one of the group is built; the actual Build calls
+// are then serialized, since di.Builder is not safe for concurrent use.
+func Parallel(bundles ...Bundle) Bundle {
+	return &parallelBundle{
+		name:     compositeName("parallel", bundles),
+		children: bundles,
+	}
+}
+
+// Name implements Bundle.
+func (p *parallelBundle) Name() string {
+	return p.name
+}
+
+// Build implements Bundle. Like seqBundle.Build, this only runs if a
+// parallelBundle is built directly rather than through an app, since
+// flattenBundles always expands it into individual graph nodes first; it
+// mirrors the concurrent-Prepare-then-serial-Build semantics that
+// flattenBundles achieves for the flattened case via parallelGroup.
+func (p *parallelBundle) Build(builder di.Builder) (err error) {
+	var group errgroup.Group
+	for _, child := range p.children {
+		if preparer, ok := child.(Preparer); ok {
+			group.Go(func() error {
+				return preparer.Prepare(context.Background())
+			})
+		}
+	}
+
+	if err = group.Wait(); err != nil {
+		return err
+	}
+
+	for _, child := range p.children {
+		if err = child.Build(builder); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DependsOn implements BundleDependsOn.
+func (p *parallelBundle) DependsOn() []string {
+	return externalDependencies(p.children)
+}
+
+// Children implements CompositeBundle.
+func (p *parallelBundle) Children() []Bundle {
+	return p.children
+}
+
+// prepare runs Prepare concurrently for every member of the group that
+// implements Preparer, the first time any member calls it; later callers
+// just observe the same result.
+func (g *parallelGroup) prepare() error {
+	g.once.Do(func() {
+		var group errgroup.Group
+		for _, member := range g.members {
+			if preparer, ok := member.(Preparer); ok {
+				group.Go(func() error {
+					return preparer.Prepare(context.Background())
+				})
+			}
+		}
+
+		g.err = group.Wait()
+	})
+
+	return g.err
+}
+
+// Build implements Bundle.
+func (m *parallelMember) Build(builder di.Builder) error {
+	if err := m.group.prepare(); err != nil {
+		return err
+	}
+
+	return m.Bundle.Build(builder)
+}
+
+// DependsOn implements BundleDependsOn, passing through the wrapped
+// bundle's dependencies, including any synthetic ones added for a Seq.
+func (m *parallelMember) DependsOn() []string {
+	if v, ok := m.Bundle.(BundleDependsOn); ok {
+		return v.DependsOn()
+	}
+
+	return nil
+}
+
+// DependsOn implements BundleDependsOn.
+func (d *dependencyBundle) DependsOn() []string {
+	var deps []string
+	if v, ok := d.Bundle.(BundleDependsOn); ok {
+		deps = append(deps, v.DependsOn()...)
+	}
+
+	return append(deps, d.extra...)
+}
+
+// flattenBundles expands CompositeBundle entries into their children, so
+// each one becomes an ordinary node in the dependency graph. A Seq's
+// children get a synthetic dependency on their preceding sibling, so the
+// existing topological sort preserves the sequence's order; a Parallel's
+// children instead share a parallelGroup, so their Preparers still run
+// concurrently despite none of them being the graph node that used to run
+// parallelBundle.Build. Duplicate names are rejected the same way Bundles
+// does.
+func flattenBundles(bundles map[string]Bundle) (map[string]Bundle, error) {
+	var flat = make(map[string]Bundle, len(bundles))
+
+	// insert returns the name and pre-wrapping bundle of every leaf it
+	// flattened, so a caller walking a Seq can depend on all of them
+	// regardless of whether that child was itself a leaf or a nested
+	// composite, and so a caller walking a Parallel can build a
+	// parallelGroup from the real bundles, not flattenBundles' own
+	// wrappers around them.
+	type leaf struct {
+		name string
+		raw  Bundle
+	}
+
+	var insert func(bundle Bundle, extra []string) ([]leaf, error)
+	insert = func(bundle Bundle, extra []string) ([]leaf, error) {
+		if composite, ok := bundle.(CompositeBundle); ok {
+			if isOrderedComposite(bundle) {
+				var prev []string
+				var all []leaf
+				for i, child := range composite.Children() {
+					var childDeps = extra
+					if i > 0 {
+						childDeps = append(append([]string{}, extra...), prev...)
+					}
+
+					leaves, err := insert(child, childDeps)
+					if err != nil {
+						return nil, err
+					}
+
+					if len(leaves) > 0 {
+						prev = make([]string, 0, len(leaves))
+						for _, lf := range leaves {
+							prev = append(prev, lf.name)
+						}
+					}
+
+					all = append(all, leaves...)
+				}
+
+				return all, nil
+			}
+
+			// Parallel: children are independent of one another, so no
+			// synthetic dependency is added between them, but they share
+			// a parallelGroup so their Preparers still run concurrently.
+			var group = &parallelGroup{}
+			var all []leaf
+			for _, child := range composite.Children() {
+				leaves, err := insert(child, extra)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, lf := range leaves {
+					group.members = append(group.members, lf.raw)
+
+					// a leaf of a nested Parallel joins this group instead
+					// of being wrapped, and prepared, a second time
+					if member, ok := flat[lf.name].(*parallelMember); ok {
+						member.group = group
+						continue
+					}
+
+					flat[lf.name] = &parallelMember{Bundle: flat[lf.name], group: group}
+				}
+
+				all = append(all, leaves...)
+			}
+
+			return all, nil
+		}
+
+		if _, ok := flat[bundle.Name()]; ok {
+			return nil, fmt.Errorf(`trying to register two bundles with the same name "%s"`, bundle.Name())
+		}
+
+		var raw = bundle
+		if len(extra) > 0 {
+			bundle = &dependencyBundle{Bundle: bundle, extra: extra}
+		}
+
+		flat[bundle.Name()] = bundle
+		return []leaf{{name: bundle.Name(), raw: raw}}, nil
+	}
+
+	for _, bundle := range bundles {
+		if _, err := insert(bundle, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return flat, nil
+}
+
+// isOrderedComposite reports whether the composite must preserve the
+// build order of its children, as opposed to Parallel, where children
+// are independent.
+func isOrderedComposite(bundle Bundle) bool {
+	_, ok := bundle.(*seqBundle)
+	return ok
+}
+
+// externalDependencies is the union of the children's dependencies, minus
+// names internal to the composite, including those of nested composites'
+// children.
+func externalDependencies(children []Bundle) []string {
+	var internal = make(map[string]struct{}, len(children))
+	var collect func(bundles []Bundle)
+	collect = func(bundles []Bundle) {
+		for _, bundle := range bundles {
+			internal[bundle.Name()] = struct{}{}
+			if composite, ok := bundle.(CompositeBundle); ok {
+				collect(composite.Children())
+			}
+		}
+	}
+
+	collect(children)
+
+	var seen = make(map[string]struct{})
+	var deps []string
+	for _, child := range children {
+		v, ok := child.(BundleDependsOn)
+		if !ok {
+			continue
+		}
+
+		for _, name := range v.DependsOn() {
+			if _, ok := internal[name]; ok {
+				continue
+			}
+
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			seen[name] = struct{}{}
+			deps = append(deps, name)
+		}
+	}
+
+	return deps
+}
+
+// compositeName builds a readable synthetic name for a Seq or Parallel
+// bundle, e.g. "seq(a,b,c)".
+func compositeName(kind string, children []Bundle) string {
+	var names = make([]string, len(children))
+	for i, child := range children {
+		names[i] = child.Name()
+	}
+
+	return fmt.Sprintf("%s(%s)", kind, strings.Join(names, ","))
+}
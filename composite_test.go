@@ -0,0 +1,144 @@
+package glue_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gozix/di"
+	"github.com/gozix/glue/v2"
+	glueMock "github.com/gozix/glue/v2/mock"
+)
+
+// preparerBundle is a hand-written Bundle+Preparer, since glueMock.Bundle
+// doesn't implement Preparer and mock.Mock can't prove concurrency.
+type preparerBundle struct {
+	name    string
+	prepare func(ctx context.Context) error
+}
+
+func (p *preparerBundle) Name() string             { return p.name }
+func (p *preparerBundle) Build(_ di.Builder) error { return nil }
+func (p *preparerBundle) Prepare(ctx context.Context) error {
+	return p.prepare(ctx)
+}
+
+func TestSeq(t *testing.T) {
+	var order []string
+
+	var a = new(glueMock.Bundle)
+	a.On("Name").Return("a")
+	a.On("Build", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		order = append(order, "a")
+	})
+
+	var b = new(glueMock.Bundle)
+	b.On("Name").Return("b")
+	b.On("Build", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		order = append(order, "b")
+	})
+
+	var _, err = glue.NewApp(
+		glue.Bundles(glue.Seq(a, b)),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b"}, order)
+}
+
+func TestSeqNested(t *testing.T) {
+	var order []string
+
+	var a = new(glueMock.Bundle)
+	a.On("Name").Return("a")
+	a.On("Build", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		order = append(order, "a")
+	})
+
+	var b = new(glueMock.Bundle)
+	b.On("Name").Return("b")
+	b.On("Build", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		order = append(order, "b")
+	})
+
+	var c = new(glueMock.Bundle)
+	c.On("Name").Return("c")
+	c.On("Build", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		order = append(order, "c")
+	})
+
+	var _, err = glue.NewApp(
+		glue.Bundles(glue.Seq(glue.Seq(a, b), c)),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+}
+
+func TestParallel(t *testing.T) {
+	var a = new(glueMock.Bundle)
+	a.On("Name").Return("a")
+	a.On("Build", mock.Anything).Return(nil)
+
+	var b = new(glueMock.Bundle)
+	b.On("Name").Return("b")
+	b.On("Build", mock.Anything).Return(nil)
+
+	var _, err = glue.NewApp(
+		glue.Bundles(glue.Parallel(a, b)),
+	)
+
+	assert.Nil(t, err)
+}
+
+// TestParallelPrepareConcurrent proves that Parallel actually runs its
+// children's Prepare methods concurrently: each one blocks until it has
+// observed the other start, so the test deadlocks (and is failed by its
+// own timeout) if flattenBundles ever goes back to building them serially.
+func TestParallelPrepareConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals int
+	var arrived = make(chan struct{})
+
+	// wait blocks until both children have reached this point, so a
+	// serial Build (where the second Prepare only starts after the first
+	// one returns) can never close arrived and times the test out.
+	var wait = func(ctx context.Context) error {
+		mu.Lock()
+		arrivals++
+		if arrivals == 2 {
+			close(arrived)
+		}
+		mu.Unlock()
+
+		select {
+		case <-arrived:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		return nil
+	}
+
+	var a = &preparerBundle{name: "a", prepare: wait}
+	var b = &preparerBundle{name: "b", prepare: wait}
+
+	var done = make(chan error, 1)
+	go func() {
+		var _, err = glue.NewApp(
+			glue.Bundles(glue.Parallel(a, b)),
+		)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for concurrent Prepare")
+	}
+}
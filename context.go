@@ -0,0 +1,36 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package glue
+
+import "context"
+
+// contextKey is the type of the app's own context keys, so they can never
+// collide with keys set by bundles or callers.
+type contextKey string
+
+const (
+	// ctxAppPath is the key for the app's working directory.
+	ctxAppPath contextKey = "app.path"
+
+	// ctxAppVersion is the key for the app's Version option value.
+	ctxAppVersion contextKey = "app.version"
+
+	// ctxAppLogger is the key for the resolved Logger.
+	ctxAppLogger contextKey = "app.logger"
+
+	// ctxCliCmd is the key for the cobra.Command currently running.
+	ctxCliCmd contextKey = "cli.cmd"
+
+	// ctxCliArgs is the key for the positional args of the command
+	// currently running.
+	ctxCliArgs contextKey = "cli.args"
+)
+
+// FromContext retrieves a typed value from ctx, replacing the
+// ctx.Value(key).(T) assertion pattern.
+func FromContext[T any](ctx context.Context, key interface{}) (value T, ok bool) {
+	value, ok = ctx.Value(key).(T)
+	return value, ok
+}
@@ -0,0 +1,23 @@
+package glue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gozix/glue/v2"
+)
+
+func TestFromContext(t *testing.T) {
+	type key string
+
+	var ctx = context.WithValue(context.Background(), key("k"), "v")
+
+	v, ok := glue.FromContext[string](ctx, key("k"))
+	assert.True(t, ok)
+	assert.Equal(t, "v", v)
+
+	_, ok = glue.FromContext[int](ctx, key("k"))
+	assert.False(t, ok)
+}
@@ -18,6 +18,16 @@ const (
 
 	// tagPersistentPreRunner is tag to mark persistent prerunners
 	tagPersistentPreRunner = "cli.persistent_prerunner"
+
+	// tagRunner is tag to mark lifecycle Runner services.
+	tagRunner = "glue.runner"
+
+	// tagStopper is tag to mark lifecycle Stopper services.
+	tagStopper = "glue.stopper"
+
+	// tagLogFlags is tag to mark the log level/format FlagSet specifically,
+	// in addition to the generic tagPersistentFlags.
+	tagLogFlags = "glue.log_flags"
 )
 
 // AsCliCommand is syntax sugar for the di container.
@@ -41,12 +51,36 @@ func AsPersistentPreRunner() di.ProvideOption {
 	}}
 }
 
+// AsRunner is syntax sugar for the di container.
+func AsRunner() di.ProvideOption {
+	return di.Tags{{
+		Name: tagRunner,
+	}}
+}
+
+// AsStopper is syntax sugar for the di container.
+func AsStopper() di.ProvideOption {
+	return di.Tags{{
+		Name: tagStopper,
+	}}
+}
+
 func asRootCommand() di.ProvideOption {
 	return di.Tags{{
 		Name: tagCliRootCommand,
 	}}
 }
 
+// asLogFlags tags the log level/format FlagSet both as a generic persistent
+// FlagSet and, specifically, as the log FlagSet.
+func asLogFlags() di.ProvideOption {
+	return di.Tags{{
+		Name: tagPersistentFlags,
+	}, {
+		Name: tagLogFlags,
+	}}
+}
+
 func withCliCommand() di.Modifier {
 	return di.WithTags(tagCliCommand)
 }
@@ -62,3 +96,15 @@ func withPersistentPreRunner() di.Modifier {
 func withRootCommand() di.Modifier {
 	return di.WithTags(tagCliRootCommand)
 }
+
+func withRunner() di.Modifier {
+	return di.WithTags(tagRunner)
+}
+
+func withStopper() di.Modifier {
+	return di.WithTags(tagStopper)
+}
+
+func withLogFlags() di.Modifier {
+	return di.WithTags(tagLogFlags)
+}
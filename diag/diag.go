@@ -0,0 +1,145 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package diag provides a small structured diagnostics type shared between
+// bundles and prerunners that need to report problems without necessarily
+// aborting application startup.
+package diag
+
+import "fmt"
+
+type (
+	// Severity is the level of a Diagnostic.
+	Severity int
+
+	// Diagnostic is a single structured message produced by a bundle or
+	// prerunner, e.g. deprecated config, a missing optional integration or
+	// a partial init.
+	Diagnostic struct {
+		// Severity of the diagnostic.
+		Severity Severity
+
+		// Summary is a short, one line description of the diagnostic.
+		Summary string
+
+		// Detail is an optional, longer description.
+		Detail string
+
+		// Source is the name of the bundle that produced the diagnostic.
+		// It is optional and may be filled in by the caller when absent.
+		Source string
+	}
+
+	// Diagnostics is a collection of Diagnostic.
+	Diagnostics []Diagnostic
+)
+
+const (
+	// SeverityError marks a diagnostic that must abort application startup.
+	SeverityError Severity = iota
+
+	// SeverityWarning marks a non-fatal diagnostic.
+	SeverityWarning
+
+	// SeverityInfo marks an informational diagnostic.
+	SeverityInfo
+)
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "unknown"
+	}
+}
+
+// Errorf builds a Diagnostic with SeverityError.
+func Errorf(summary, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityError,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
+
+// Warningf builds a Diagnostic with SeverityWarning.
+func Warningf(summary, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityWarning,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
+
+// Infof builds a Diagnostic with SeverityInfo.
+func Infof(summary, format string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: SeverityInfo,
+		Summary:  summary,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
+
+// HasError reports whether the collection contains at least one diagnostic
+// with SeverityError.
+func (d Diagnostics) HasError() bool {
+	for i := range d {
+		if d[i].Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasWarning reports whether the collection contains at least one diagnostic
+// with SeverityWarning.
+func (d Diagnostics) HasWarning() bool {
+	for i := range d {
+		if d[i].Severity == SeverityWarning {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Errors returns the subset of d with SeverityError, so a caller that
+// aborts on HasError can report just the errors, without folding
+// previously-collected warnings or info diagnostics into the failure.
+func (d Diagnostics) Errors() Diagnostics {
+	var errs Diagnostics
+	for i := range d {
+		if d[i].Severity == SeverityError {
+			errs = append(errs, d[i])
+		}
+	}
+
+	return errs
+}
+
+// Error implements the error interface, so Diagnostics can be returned
+// wherever a plain error is expected.
+func (d Diagnostics) Error() string {
+	var msg string
+	for i := range d {
+		if i > 0 {
+			msg += "; "
+		}
+
+		if d[i].Source != "" {
+			msg += fmt.Sprintf("[%s] %s: %s", d[i].Source, d[i].Severity, d[i].Summary)
+		} else {
+			msg += fmt.Sprintf("%s: %s", d[i].Severity, d[i].Summary)
+		}
+	}
+
+	return msg
+}
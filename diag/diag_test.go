@@ -0,0 +1,26 @@
+package diag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gozix/glue/v2/diag"
+)
+
+func TestDiagnostics_HasError(t *testing.T) {
+	assert.False(t, diag.Diagnostics{diag.Warningf("a", "b")}.HasError())
+	assert.True(t, diag.Diagnostics{diag.Errorf("a", "b")}.HasError())
+}
+
+func TestDiagnostics_HasWarning(t *testing.T) {
+	assert.False(t, diag.Diagnostics{diag.Infof("a", "b")}.HasWarning())
+	assert.True(t, diag.Diagnostics{diag.Warningf("a", "b")}.HasWarning())
+}
+
+func TestDiagnostics_Errors(t *testing.T) {
+	var errDiag = diag.Errorf("a", "b")
+
+	var d = diag.Diagnostics{diag.Warningf("c", "d"), errDiag, diag.Infof("e", "f")}
+	assert.Equal(t, diag.Diagnostics{errDiag}, d.Errors())
+}
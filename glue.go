@@ -13,10 +13,14 @@ import (
 	"path/filepath"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gozix/di"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gozix/glue/v2/diag"
 )
 
 //go:generate mockery --case=underscore --output=mock --outpkg=mock --name=Bundle|BundleDependsOn
@@ -28,6 +32,7 @@ type (
 
 	InternalApp interface {
 		Container() di.Container
+		Diagnostics() diag.Diagnostics
 		Init() error
 		Run() error
 		Stop()
@@ -58,14 +63,50 @@ type (
 	// PreRunnerFunc is syntax sugar for usage PreRunner.
 	PreRunnerFunc func(ctx context.Context) error
 
+	// BundleDiagnoser is an optional Bundle interface, that allows a bundle
+	// to report non-fatal diagnostics (deprecated config, missing optional
+	// integrations, partial init) alongside its Build call.
+	BundleDiagnoser interface {
+		BuildDiag(builder di.Builder) diag.Diagnostics
+	}
+
+	// PreRunnerDiagnoser is an optional PreRunner interface, that allows a
+	// prerunner to report non-fatal diagnostics alongside its Run call.
+	PreRunnerDiagnoser interface {
+		RunDiag(ctx context.Context) diag.Diagnostics
+	}
+
+	// Runner is a lifecycle interface for a service that runs for the
+	// lifetime of the app, e.g. an HTTP server or a queue consumer. All
+	// tagged Runners are started before the resolved cobra.Command
+	// executes, regardless of which subcommand was invoked - so even a
+	// metadata command like "version" or "--help" starts, then
+	// immediately stops, every Runner.
+	Runner interface {
+		Start(ctx context.Context) error
+	}
+
+	// Stopper is the shutdown counterpart of Runner.
+	Stopper interface {
+		Stop(ctx context.Context) error
+	}
+
 	// app is implementation of App.
 	app struct {
-		ctx       context.Context
-		cancel    context.CancelFunc
-		mux       sync.Mutex
-		bundles   map[string]Bundle
-		builder   di.Builder
-		container di.Container
+		ctx             context.Context
+		cancel          context.CancelFunc
+		mux             sync.Mutex
+		bundles         map[string]Bundle
+		builder         di.Builder
+		container       di.Container
+		diagnostics     diag.Diagnostics
+		shutdownTimeout time.Duration
+		signals         []os.Signal
+		onSignal        func(sig os.Signal, a InternalApp)
+		logFlags        *pflag.FlagSet
+		logger          Logger
+		runners         []Runner
+		stoppers        []Stopper
 	}
 
 	// optionFunc wraps a func, so it satisfies the Option interface.
@@ -79,6 +120,11 @@ var (
 	_ PreRunner = (*PreRunnerFunc)(nil)
 )
 
+// defaultSignalGracePeriod is how long Execute waits, after the first
+// shutdown signal, before a repeated signal forces an immediate exit.
+// It is overridden by ShutdownTimeout, when set.
+const defaultSignalGracePeriod = 10 * time.Second
+
 // Context option.
 func Context(ctx context.Context) Option {
 	return optionFunc(func(a *app) error {
@@ -109,7 +155,36 @@ func Bundles(bundles ...Bundle) Option {
 // Version option.
 func Version(version string) Option {
 	return optionFunc(func(a *app) error {
-		a.withValue("app.version", version)
+		a.withValue(ctxAppVersion, version)
+		return nil
+	})
+}
+
+// ShutdownTimeout option sets how long run waits for lifecycle Stoppers to
+// return, for Runners to return once their context is cancelled, and for
+// the container to close, before returning control to the caller. Zero,
+// the default, means wait indefinitely.
+func ShutdownTimeout(d time.Duration) Option {
+	return optionFunc(func(a *app) error {
+		a.shutdownTimeout = d
+		return nil
+	})
+}
+
+// Signals option overrides which OS signals Execute treats as a shutdown
+// request. The default is SIGINT and SIGTERM.
+func Signals(sigs ...os.Signal) Option {
+	return optionFunc(func(a *app) error {
+		a.signals = sigs
+		return nil
+	})
+}
+
+// OnSignal option registers a callback invoked whenever Execute receives a
+// configured signal, before the app context is cancelled.
+func OnSignal(fn func(sig os.Signal, a InternalApp)) Option {
+	return optionFunc(func(a *app) error {
+		a.onSignal = fn
 		return nil
 	})
 }
@@ -126,8 +201,12 @@ func newApp(options ...Option) (*app, error) {
 	var a = app{
 		ctx:     context.Background(),
 		bundles: make(map[string]Bundle, 8),
+		signals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
 	}
 
+	a.logFlags = newLogFlags()
+	a.logger = newDeferredFlagLogger(a.logFlags)
+
 	// apply options
 	var err error
 	for _, option := range options {
@@ -154,12 +233,38 @@ func (a *app) Execute() error {
 	a.mux.Lock()
 	defer a.mux.Unlock()
 
-	// wait signal, cancel execution context
+	// wait signal, cancel execution context; a second signal within the
+	// grace period forces an immediate exit instead of waiting out a stuck
+	// shutdown
 	var sigChan = make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, a.signals...)
+	defer signal.Stop(sigChan)
+
 	go func() {
-		<-sigChan
-		a.Stop()
+		for sig := range sigChan {
+			a.logger.Debug("signal received", String("signal", sig.String()))
+
+			if a.onSignal != nil {
+				a.onSignal(sig, a)
+			}
+
+			a.Stop()
+
+			var grace = a.shutdownTimeout
+			if grace <= 0 {
+				grace = defaultSignalGracePeriod
+			}
+
+			select {
+			case sig = <-sigChan:
+				if a.onSignal != nil {
+					a.onSignal(sig, a)
+				}
+
+				os.Exit(130)
+			case <-time.After(grace):
+			}
+		}
 	}()
 
 	err := a.init()
@@ -167,7 +272,27 @@ func (a *app) Execute() error {
 		return err
 	}
 
-	return a.run()
+	err = a.run()
+
+	a.printDiagnostics()
+
+	return err
+}
+
+// printDiagnostics writes non-fatal diagnostics (warnings and info) to
+// stderr. Errors are expected to already have aborted startup.
+func (a *app) printDiagnostics() {
+	for _, diagnostic := range a.diagnostics {
+		if diagnostic.Severity == diag.SeverityError {
+			continue
+		}
+
+		if diagnostic.Source != "" {
+			fmt.Fprintf(os.Stderr, "[%s] %s: %s\n", diagnostic.Source, diagnostic.Severity, diagnostic.Summary)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", diagnostic.Severity, diagnostic.Summary)
+		}
+	}
 }
 
 func (a *app) Init() error {
@@ -186,7 +311,7 @@ func (a *app) init() error {
 
 	// modify context
 	a.withCancel()
-	a.withValue("app.path", appPath)
+	a.withValue(ctxAppPath, appPath)
 
 	// build container
 	a.container, err = a.builder.Build()
@@ -194,6 +319,12 @@ func (a *app) init() error {
 		return err
 	}
 
+	// Debug, even though this runs before root.ExecuteContext parses
+	// --log-level and so can never be reached by it: a library must not
+	// write to stderr by default, and this runs on every invocation,
+	// including myapp version and myapp --help.
+	a.logger.Debug("container built")
+
 	return nil
 }
 
@@ -205,22 +336,79 @@ func (a *app) Run() error {
 }
 
 func (a *app) run() (err error) {
+	// resolve cli root; this also populates a.runners/a.stoppers, since
+	// provideRootCmd depends on them
+	var root *cobra.Command
+	if err = a.container.Resolve(&root, withRootCommand()); err != nil {
+		return err
+	}
+
+	// Start runners, in the order the di container resolved them (the
+	// registration order of their tagged providers, not a topological
+	// sort over the runners themselves), under a shared cancelable
+	// context. This happens unconditionally, before root.ExecuteContext
+	// below resolves which subcommand was requested, so every command -
+	// including "version" and "--help" - starts and then immediately
+	// stops every Runner; see Runner's doc comment.
+	var group, groupCtx = errgroup.WithContext(a.ctx)
+	for _, runner := range a.runners {
+		var runner = runner
+		group.Go(func() error {
+			return runner.Start(groupCtx)
+		})
+	}
+
 	defer func() {
 		a.Stop()
 
-		if err != nil {
-			_ = a.container.Close()
-			return
+		var stopCtx = context.Background()
+		if a.shutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			stopCtx, cancel = context.WithTimeout(stopCtx, a.shutdownTimeout)
+			defer cancel()
 		}
 
-		err = a.container.Close()
-	}()
+		// Run the whole shutdown sequence - stopping lifecycle runners in
+		// the reverse of their start order, waiting for the runners
+		// themselves to return, then closing the container - in one
+		// goroutine, so a single ShutdownTimeout bounds all of it. A
+		// Stopper, Runner or container.Close that ignores stopCtx/groupCtx
+		// would otherwise hang run() forever.
+		var shutdownDone = make(chan error, 1)
+		go func() {
+			var serr error
+			for i := len(a.stoppers) - 1; i >= 0; i-- {
+				if e := a.stoppers[i].Stop(stopCtx); e != nil && serr == nil {
+					serr = e
+				}
+			}
 
-	// resolve cli root
-	var root *cobra.Command
-	if err = a.container.Resolve(&root, withRootCommand()); err != nil {
-		return err
-	}
+			if e := group.Wait(); e != nil && serr == nil {
+				serr = e
+			}
+
+			if e := a.container.Close(); e != nil && serr == nil {
+				serr = e
+			}
+
+			shutdownDone <- serr
+		}()
+
+		if a.shutdownTimeout > 0 {
+			select {
+			case serr := <-shutdownDone:
+				if serr != nil && err == nil {
+					err = serr
+				}
+			case <-time.After(a.shutdownTimeout):
+				if err == nil {
+					err = fmt.Errorf("timed out after %s waiting for shutdown", a.shutdownTimeout)
+				}
+			}
+		} else if serr := <-shutdownDone; serr != nil && err == nil {
+			err = serr
+		}
+	}()
 
 	return root.ExecuteContext(a.ctx)
 }
@@ -233,34 +421,81 @@ func (a *app) Container() di.Container {
 	return a.container
 }
 
+// Diagnostics returns diagnostics collected from bundles and prerunners
+// since app creation.
+func (a *app) Diagnostics() diag.Diagnostics {
+	return a.diagnostics
+}
+
 // builder initialize di builder
 func (a *app) initBuilder() (di.Builder, error) {
 	return di.NewBuilder(
 		di.Provide(a.provideRootContext, di.Unshared()),
+		di.Provide(a.provideLogFlags, asLogFlags()),
+		di.Provide(a.provideLogger, di.Constraint(0, withLogFlags())),
 		di.Provide(
 			a.provideRootCmd,
 			di.Constraint(0, di.Optional(true), withPersistentPreRunner()),
 			di.Constraint(1, di.Optional(true), withPersistentFlags()),
 			di.Constraint(2, di.Optional(true), withCliCommand()),
+			di.Constraint(4, di.Optional(true), withRunner()),
+			di.Constraint(5, di.Optional(true), withStopper()),
 			asRootCommand(),
 		),
 		di.Provide(a.provideVersionCmd, AsCliCommand()),
 	)
 }
 
-func (a *app) provideRootCmd(preRunners []PreRunner, flagSets []*pflag.FlagSet, subCommands []*cobra.Command) *cobra.Command {
+// newLogFlags builds the --log-level/--log-format persistent flags. It is
+// called once per app, outside the di container, so that a.logger can be
+// driven by the same *pflag.FlagSet instance from the moment the app is
+// constructed, well before provideLogFlags hands it to the container.
+func newLogFlags() *pflag.FlagSet {
+	var flags = pflag.NewFlagSet("glue.log", pflag.ContinueOnError)
+	flags.String("log-level", "info", "log level (debug, info, warn, error)")
+	flags.String("log-format", "text", "log format (text, json)")
+
+	return flags
+}
+
+// provideLogFlags registers the --log-level/--log-format persistent flags.
+func (a *app) provideLogFlags() *pflag.FlagSet {
+	return a.logFlags
+}
+
+// provideLogger builds the default Logger, driven by the --log-level and
+// --log-format persistent flags.
+func (a *app) provideLogger(flags *pflag.FlagSet) Logger {
+	return newDeferredFlagLogger(flags)
+}
+
+func (a *app) provideRootCmd(preRunners []PreRunner, flagSets []*pflag.FlagSet, subCommands []*cobra.Command, logger Logger, runners []Runner, stoppers []Stopper) *cobra.Command {
+	a.runners = runners
+	a.stoppers = stoppers
+
 	var rootCmd = &cobra.Command{
 		Use:           fmt.Sprintf("%s [command]", os.Args[0]), // TODO: replace to binary name
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) (err error) {
-			a.withValue("cli.cmd", cmd)
-			a.withValue("cli.args", args)
+			a.withValue(ctxCliCmd, cmd)
+			a.withValue(ctxCliArgs, args)
+			a.withValue(ctxAppLogger, logger)
 
 			for _, preRunner := range preRunners {
+				logger.Debug("prerunner started", String("type", fmt.Sprintf("%T", preRunner)))
+
 				if err = preRunner.Run(a.ctx); err != nil {
 					return err
 				}
+
+				if diagnoser, ok := preRunner.(PreRunnerDiagnoser); ok {
+					a.diagnostics = append(a.diagnostics, diagnoser.RunDiag(a.ctx)...)
+				}
+			}
+
+			if a.diagnostics.HasError() {
+				return a.diagnostics.Errors()
 			}
 
 			return nil
@@ -285,7 +520,7 @@ func (a *app) provideVersionCmd(ctx context.Context) *cobra.Command {
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Run: func(cmd *cobra.Command, args []string) {
-			if v, ok := ctx.Value("app.version").(string); ok {
+			if v, ok := FromContext[string](ctx, ctxAppVersion); ok {
 				fmt.Println(v)
 			}
 		},
@@ -298,6 +533,11 @@ func (a *app) provideRootContext() context.Context {
 
 // registerBundles resolve bundles dependencies and register them.
 func (a *app) registerBundles() (err error) {
+	// flatten composite bundles (Seq, Parallel) into the graph
+	if a.bundles, err = flattenBundles(a.bundles); err != nil {
+		return err
+	}
+
 	// resolve dependencies
 	var (
 		resolved   = make([]string, 0, len(a.bundles))
@@ -312,9 +552,29 @@ func (a *app) registerBundles() (err error) {
 
 	// register
 	for _, name := range resolved {
-		if err = a.bundles[name].Build(a.builder); err != nil {
+		var bundle = a.bundles[name]
+		if err = bundle.Build(a.builder); err != nil {
 			return err
 		}
+
+		// Debug, even though registerBundles runs from newApp, before
+		// root.ExecuteContext parses --log-level, and so can never be
+		// reached by it: a library must not write to stderr by default.
+		a.logger.Debug("bundle registered", String("bundle", bundle.Name()))
+
+		if diagnoser, ok := bundle.(BundleDiagnoser); ok {
+			for _, diagnostic := range diagnoser.BuildDiag(a.builder) {
+				if diagnostic.Source == "" {
+					diagnostic.Source = bundle.Name()
+				}
+
+				a.diagnostics = append(a.diagnostics, diagnostic)
+			}
+		}
+	}
+
+	if a.diagnostics.HasError() {
+		return a.diagnostics.Errors()
 	}
 
 	return nil
@@ -365,8 +625,15 @@ func (a *app) withCancel() {
 }
 
 // withValue append any value to current context. Method is non thread safe.
-func (a *app) withValue(key, value interface{}) context.Context {
+//
+// Before the typed contextKey keys, these keys (ctxAppPath, ctxAppVersion,
+// ctxCliCmd, ctxCliArgs) were plain strings, and callers read them back via
+// ctx.Value("app.path") and similar. So the value is stored both under the
+// typed key and under the legacy string(key), to avoid silently breaking
+// existing callers that haven't migrated to FromContext.
+func (a *app) withValue(key contextKey, value interface{}) context.Context {
 	a.ctx = context.WithValue(a.ctx, key, value)
+	a.ctx = context.WithValue(a.ctx, string(key), value)
 	return a.ctx
 }
 
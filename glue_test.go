@@ -116,22 +116,6 @@ func TestBundles(t *testing.T) {
 	})
 }
 
-func TestScopes(t *testing.T) {
-	t.Run("PositiveCase1", func(t *testing.T) {
-		var _, err = glue.NewApp(
-			glue.Scopes("a", "b"),
-		)
-		assert.Nil(t, err)
-	})
-
-	t.Run("NegativeCase1", func(t *testing.T) {
-		var _, err = glue.NewApp(
-			glue.Scopes("a", "a"),
-		)
-		assert.Error(t, err)
-	})
-}
-
 func TestExecute(t *testing.T) {
 	var captureStdout = func(fn func() error) (_ []byte, err error) {
 		var oStdout = *os.Stdout
@@ -0,0 +1,112 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+package glue
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/pflag"
+)
+
+type (
+	// Logger is a structured logging interface for the app and its
+	// bundles. Adapters for slog, zap and zerolog live under glue/log/...;
+	// the default, used when no adapter is configured, is backed by the
+	// standard library log/slog package and driven by the --log-level and
+	// --log-format persistent flags.
+	Logger interface {
+		Debug(msg string, fields ...Field)
+		Info(msg string, fields ...Field)
+		Warn(msg string, fields ...Field)
+		Error(msg string, fields ...Field)
+	}
+
+	// Field is a single structured logging key/value pair.
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+
+	// slogLogger is the built-in Logger, backed by log/slog.
+	slogLogger struct {
+		logger *slog.Logger
+	}
+
+	// deferredFlagLogger re-reads --log-level/--log-format from flags on
+	// every call, since cobra only parses them once root.ExecuteContext
+	// runs, well after this Logger is built by the di container.
+	deferredFlagLogger struct {
+		flags *pflag.FlagSet
+	}
+)
+
+// String builds a string Field.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err builds an "error" Field.
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// newDefaultLogger builds the built-in Logger for the given level
+// ("debug", "info", "warn", "error") and format ("text", "json").
+func newDefaultLogger(level, format string) Logger {
+	var opts = &slog.HandlerOptions{Level: parseLogLevel(level)}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+
+	return &slogLogger{logger: slog.New(handler)}
+}
+
+func parseLogLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func toSlogArgs(fields []Field) []interface{} {
+	var args = make([]interface{}, 0, len(fields)*2)
+	for _, field := range fields {
+		args = append(args, field.Key, field.Value)
+	}
+
+	return args
+}
+
+func (l *slogLogger) Debug(msg string, fields ...Field) { l.logger.Debug(msg, toSlogArgs(fields)...) }
+func (l *slogLogger) Info(msg string, fields ...Field)  { l.logger.Info(msg, toSlogArgs(fields)...) }
+func (l *slogLogger) Warn(msg string, fields ...Field)  { l.logger.Warn(msg, toSlogArgs(fields)...) }
+func (l *slogLogger) Error(msg string, fields ...Field) { l.logger.Error(msg, toSlogArgs(fields)...) }
+
+func newDeferredFlagLogger(flags *pflag.FlagSet) Logger {
+	return &deferredFlagLogger{flags: flags}
+}
+
+func (l *deferredFlagLogger) resolve() Logger {
+	var level, _ = l.flags.GetString("log-level")
+	var format, _ = l.flags.GetString("log-format")
+
+	return newDefaultLogger(level, format)
+}
+
+func (l *deferredFlagLogger) Debug(msg string, fields ...Field) { l.resolve().Debug(msg, fields...) }
+func (l *deferredFlagLogger) Info(msg string, fields ...Field)  { l.resolve().Info(msg, fields...) }
+func (l *deferredFlagLogger) Warn(msg string, fields ...Field)  { l.resolve().Warn(msg, fields...) }
+func (l *deferredFlagLogger) Error(msg string, fields ...Field) { l.resolve().Error(msg, fields...) }
@@ -0,0 +1,51 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package slog adapts a *slog.Logger into a glue.Logger.
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/gozix/glue/v2"
+)
+
+// adapter is a glue.Logger backed by a *slog.Logger.
+type adapter struct {
+	logger *slog.Logger
+}
+
+// New wraps logger as a glue.Logger.
+func New(logger *slog.Logger) glue.Logger {
+	return &adapter{logger: logger}
+}
+
+// Debug implements glue.Logger.
+func (a *adapter) Debug(msg string, fields ...glue.Field) {
+	a.logger.Debug(msg, toArgs(fields)...)
+}
+
+// Info implements glue.Logger.
+func (a *adapter) Info(msg string, fields ...glue.Field) {
+	a.logger.Info(msg, toArgs(fields)...)
+}
+
+// Warn implements glue.Logger.
+func (a *adapter) Warn(msg string, fields ...glue.Field) {
+	a.logger.Warn(msg, toArgs(fields)...)
+}
+
+// Error implements glue.Logger.
+func (a *adapter) Error(msg string, fields ...glue.Field) {
+	a.logger.Error(msg, toArgs(fields)...)
+}
+
+func toArgs(fields []glue.Field) []interface{} {
+	var args = make([]interface{}, 0, len(fields)*2)
+	for _, field := range fields {
+		args = append(args, field.Key, field.Value)
+	}
+
+	return args
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package zap adapts a *zap.Logger into a glue.Logger.
+package zap
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/gozix/glue/v2"
+)
+
+// adapter is a glue.Logger backed by a *zap.Logger.
+type adapter struct {
+	logger *zap.Logger
+}
+
+// New wraps logger as a glue.Logger.
+func New(logger *zap.Logger) glue.Logger {
+	return &adapter{logger: logger}
+}
+
+// Debug implements glue.Logger.
+func (a *adapter) Debug(msg string, fields ...glue.Field) {
+	a.logger.Debug(msg, toZapFields(fields)...)
+}
+
+// Info implements glue.Logger.
+func (a *adapter) Info(msg string, fields ...glue.Field) {
+	a.logger.Info(msg, toZapFields(fields)...)
+}
+
+// Warn implements glue.Logger.
+func (a *adapter) Warn(msg string, fields ...glue.Field) {
+	a.logger.Warn(msg, toZapFields(fields)...)
+}
+
+// Error implements glue.Logger.
+func (a *adapter) Error(msg string, fields ...glue.Field) {
+	a.logger.Error(msg, toZapFields(fields)...)
+}
+
+func toZapFields(fields []glue.Field) []zap.Field {
+	var zapFields = make([]zap.Field, 0, len(fields))
+	for _, field := range fields {
+		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
+	}
+
+	return zapFields
+}
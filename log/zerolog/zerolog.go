@@ -0,0 +1,50 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package zerolog adapts a zerolog.Logger into a glue.Logger.
+package zerolog
+
+import (
+	"github.com/rs/zerolog"
+
+	"github.com/gozix/glue/v2"
+)
+
+// adapter is a glue.Logger backed by a zerolog.Logger.
+type adapter struct {
+	logger zerolog.Logger
+}
+
+// New wraps logger as a glue.Logger.
+func New(logger zerolog.Logger) glue.Logger {
+	return &adapter{logger: logger}
+}
+
+// Debug implements glue.Logger.
+func (a *adapter) Debug(msg string, fields ...glue.Field) {
+	a.log(a.logger.Debug(), msg, fields)
+}
+
+// Info implements glue.Logger.
+func (a *adapter) Info(msg string, fields ...glue.Field) {
+	a.log(a.logger.Info(), msg, fields)
+}
+
+// Warn implements glue.Logger.
+func (a *adapter) Warn(msg string, fields ...glue.Field) {
+	a.log(a.logger.Warn(), msg, fields)
+}
+
+// Error implements glue.Logger.
+func (a *adapter) Error(msg string, fields ...glue.Field) {
+	a.log(a.logger.Error(), msg, fields)
+}
+
+func (a *adapter) log(event *zerolog.Event, msg string, fields []glue.Field) {
+	for _, field := range fields {
+		event = event.Interface(field.Key, field.Value)
+	}
+
+	event.Msg(msg)
+}
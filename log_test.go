@@ -0,0 +1,23 @@
+package glue_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gozix/glue/v2"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	var k, err = glue.NewInternalApp()
+	assert.Nil(t, err)
+	assert.Nil(t, k.Init())
+
+	var logger glue.Logger
+	assert.Nil(t, k.Container().Resolve(&logger))
+
+	assert.NotPanics(t, func() {
+		logger.Debug("test", glue.String("key", "value"))
+		logger.Info("test", glue.Err(assert.AnError))
+	})
+}
@@ -8,7 +8,8 @@ import (
 
 type (
 	// Registry interface.
-	// Deprecated: use Context instead of Registry. Will be removed in 3.0.
+	// Deprecated: use Context, or glue/typed.Get/Resolve against the di
+	// container, instead of Registry. Will be removed in 3.0.
 	Registry interface {
 		Get(name string) interface{}
 		Set(name string, value interface{})
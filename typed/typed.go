@@ -0,0 +1,35 @@
+// Copyright 2018 Sergey Novichkov. All rights reserved.
+// For the full copyright and license information, please view the LICENSE
+// file that was distributed with this source code.
+
+// Package typed provides generics-based helpers for resolving values out
+// of a di.Container, replacing the reflect-and-recover based
+// glue.Registry.Fill.
+package typed
+
+import (
+	"fmt"
+
+	"github.com/gozix/di"
+)
+
+// Get resolves a value of type T from the container.
+func Get[T any](c di.Container, mods ...di.Modifier) (value T, err error) {
+	err = c.Resolve(&value, mods...)
+	return value, err
+}
+
+// MustGet is like Get, but panics instead of returning an error.
+func MustGet[T any](c di.Container, mods ...di.Modifier) T {
+	value, err := Get[T](c, mods...)
+	if err != nil {
+		panic(fmt.Sprintf("typed: %s", err))
+	}
+
+	return value
+}
+
+// Resolve resolves a value of type T from the container into target.
+func Resolve[T any](c di.Container, target *T, mods ...di.Modifier) error {
+	return c.Resolve(target, mods...)
+}
@@ -0,0 +1,24 @@
+package typed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gozix/glue/v2"
+	"github.com/gozix/glue/v2/typed"
+)
+
+func TestGet(t *testing.T) {
+	var k, err = glue.NewInternalApp(glue.Version("1.2.3"))
+	assert.Nil(t, err)
+	assert.Nil(t, k.Init())
+
+	var logger, getErr = typed.Get[glue.Logger](k.Container())
+	assert.Nil(t, getErr)
+	assert.NotNil(t, logger)
+
+	assert.NotPanics(t, func() {
+		typed.MustGet[glue.Logger](k.Container())
+	})
+}